@@ -0,0 +1,100 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptionalBoolJSON(t *testing.T) {
+	cases := []struct {
+		val  OptionalBool
+		want string
+	}{
+		{Unset, "null"},
+		{True, "true"},
+		{False, "false"},
+	}
+	for _, c := range cases {
+		buf, err := json.Marshal(c.val)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", c.val, err)
+		}
+		if string(buf) != c.want {
+			t.Errorf("Marshal(%v) = %s, want %s", c.val, buf, c.want)
+		}
+
+		var got OptionalBool
+		if err := json.Unmarshal(buf, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", buf, err)
+		}
+		if got != c.val {
+			t.Errorf("Unmarshal(%s) = %v, want %v", buf, got, c.val)
+		}
+	}
+}
+
+func TestComponentOmitsUnsetFlags(t *testing.T) {
+	buf, err := json.Marshal(Component{Text: "hi"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	const want = `{"text":"hi"}`
+	if string(buf) != want {
+		t.Errorf("Marshal(unset flags) = %s, want %s", buf, want)
+	}
+
+	buf, err = json.Marshal(Component{Text: "hi", Bold: False})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	const wantBold = `{"text":"hi","bold":false}`
+	if string(buf) != wantBold {
+		t.Errorf("Marshal(Bold: False) = %s, want %s", buf, wantBold)
+	}
+}
+
+func TestResolveInheritsUnsetFields(t *testing.T) {
+	parent := Component{Color: "red", Bold: True}
+	child := Component{Text: "hi"}
+	r := child.Resolve(&parent)
+	if r.Color != "red" || r.Bold != True {
+		t.Errorf("Resolve: got Color=%q Bold=%v, want Color=red Bold=True", r.Color, r.Bold)
+	}
+
+	// An explicit child value must win over the parent's.
+	child.Bold = False
+	r = child.Resolve(&parent)
+	if r.Bold != False {
+		t.Errorf("Resolve: explicit False was overridden by parent, got %v", r.Bold)
+	}
+}
+
+func TestParseLegacy(t *testing.T) {
+	got := ParseLegacy("§cRed §lBold")
+	want := Component{Extra: []Component{
+		{Color: "red", Text: "Red "},
+		{Color: "red", Bold: True, Text: "Bold"},
+	}}
+	if len(got.Extra) != len(want.Extra) {
+		t.Fatalf("ParseLegacy: got %d runs, want %d: %+v", len(got.Extra), len(want.Extra), got)
+	}
+	for i := range want.Extra {
+		if got.Extra[i].Color != want.Extra[i].Color || got.Extra[i].Bold != want.Extra[i].Bold || got.Extra[i].Text != want.Extra[i].Text {
+			t.Errorf("ParseLegacy run %d: got %+v, want %+v", i, got.Extra[i], want.Extra[i])
+		}
+	}
+}
+
+func TestFormatLegacyRoundTrip(t *testing.T) {
+	c := Component{Color: "red", Bold: True, Text: "hi"}
+	s := FormatLegacy(c)
+	const want = "§c§lhi"
+	if s != want {
+		t.Fatalf("FormatLegacy = %q, want %q", s, want)
+	}
+
+	got := ParseLegacy(s)
+	if got.Color != "red" || got.Bold != True || got.Text != "hi" {
+		t.Errorf("ParseLegacy(FormatLegacy(c)) = %+v, want Color=red Bold=True Text=hi", got)
+	}
+}
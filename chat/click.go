@@ -0,0 +1,18 @@
+package chat
+
+// ClickAction is the set of actions a ClickEvent can perform.
+type ClickAction string
+
+const (
+	OpenURL         ClickAction = "open_url"
+	RunCommand      ClickAction = "run_command"
+	SuggestCommand  ClickAction = "suggest_command"
+	ChangePage      ClickAction = "change_page"
+	CopyToClipboard ClickAction = "copy_to_clipboard"
+)
+
+// ClickEvent fires when a player clicks a component in chat.
+type ClickEvent struct {
+	Action ClickAction `json:"action"`
+	Value  string      `json:"value"`
+}
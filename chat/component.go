@@ -0,0 +1,57 @@
+// Package chat implements the JSON text-component tree Minecraft uses for
+// chat messages, titles, tab-list entries, book pages, sign text and more.
+package chat
+
+// Component is a Minecraft text component. Fields use omitempty so a
+// component round-trips through JSON the same way vanilla does: absent
+// keys, not explicit zero values.
+type Component struct {
+	Text      string      `json:"text,omitempty"`
+	Translate string      `json:"translate,omitempty"`
+	With      []Component `json:"with,omitempty"`
+	Extra     []Component `json:"extra,omitempty"`
+
+	Color         string       `json:"color,omitempty"`
+	Bold          OptionalBool `json:"bold,omitempty"`
+	Italic        OptionalBool `json:"italic,omitempty"`
+	Underlined    OptionalBool `json:"underlined,omitempty"`
+	Strikethrough OptionalBool `json:"strikethrough,omitempty"`
+	Obfuscated    OptionalBool `json:"obfuscated,omitempty"`
+
+	ClickEvent *ClickEvent `json:"clickEvent,omitempty"`
+	HoverEvent *HoverEvent `json:"hoverEvent,omitempty"`
+}
+
+// Resolve returns a copy of c with every formatting flag and the color
+// inherited from parent wherever c itself leaves them unset. Pass nil for
+// the root component.
+func (c Component) Resolve(parent *Component) Component {
+	if parent == nil {
+		return c
+	}
+	r := c
+	r.Bold = r.Bold.orElse(parent.Bold)
+	r.Italic = r.Italic.orElse(parent.Italic)
+	r.Underlined = r.Underlined.orElse(parent.Underlined)
+	r.Strikethrough = r.Strikethrough.orElse(parent.Strikethrough)
+	r.Obfuscated = r.Obfuscated.orElse(parent.Obfuscated)
+	if r.Color == "" {
+		r.Color = parent.Color
+	}
+	return r
+}
+
+// ResolveTree returns c with style resolved through every Extra child,
+// recursively, so a renderer never has to consult an ancestor itself.
+func (c Component) ResolveTree(parent *Component) Component {
+	r := c.Resolve(parent)
+	if len(r.Extra) == 0 {
+		return r
+	}
+	extra := make([]Component, len(r.Extra))
+	for i, e := range r.Extra {
+		extra[i] = e.ResolveTree(&r)
+	}
+	r.Extra = extra
+	return r
+}
@@ -0,0 +1,23 @@
+package chat
+
+import "encoding/json"
+
+// HoverAction is the set of actions a HoverEvent can perform.
+type HoverAction string
+
+const (
+	ShowText   HoverAction = "show_text"
+	ShowItem   HoverAction = "show_item"
+	ShowEntity HoverAction = "show_entity"
+)
+
+// HoverEvent fires when a player hovers over a component in chat. The
+// payload's shape depends on Action and, for ShowItem/ShowEntity, on the
+// protocol version, so it's kept as raw JSON rather than a fixed struct;
+// Contents holds the modern ("contents") form and Value the pre-1.16
+// ("value") form used only by ShowText.
+type HoverEvent struct {
+	Action   HoverAction      `json:"action"`
+	Contents *json.RawMessage `json:"contents,omitempty"`
+	Value    *Component       `json:"value,omitempty"`
+}
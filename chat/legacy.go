@@ -0,0 +1,118 @@
+package chat
+
+import "strings"
+
+// legacyChar is the formatting-code prefix vanilla uses in plain strings
+// (shown in-game as §).
+const legacyChar = '§'
+
+// legacyColors maps a §-code to the vanilla color name it selects.
+var legacyColors = map[byte]string{
+	'0': "black", '1': "dark_blue", '2': "dark_green", '3': "dark_aqua",
+	'4': "dark_red", '5': "dark_purple", '6': "gold", '7': "gray",
+	'8': "dark_gray", '9': "blue", 'a': "green", 'b': "aqua",
+	'c': "red", 'd': "light_purple", 'e': "yellow", 'f': "white",
+}
+
+// ParseLegacy converts an old-style §-coded string into a Component,
+// starting a new Extra sibling each time the active formatting changes.
+func ParseLegacy(s string) Component {
+	var extra []Component
+	cur := Component{}
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		next := cur
+		next.Text = text.String()
+		extra = append(extra, next)
+		text.Reset()
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == legacyChar && i+1 < len(runes) {
+			flush()
+			code := byte(strings.ToLower(string(runes[i+1]))[0])
+			switch code {
+			case 'r':
+				cur = Component{}
+			case 'k':
+				cur.Obfuscated = True
+			case 'l':
+				cur.Bold = True
+			case 'm':
+				cur.Strikethrough = True
+			case 'n':
+				cur.Underlined = True
+			case 'o':
+				cur.Italic = True
+			default:
+				if name, ok := legacyColors[code]; ok {
+					// Vanilla resets bold/italic/etc. whenever a color code
+					// is applied.
+					cur = Component{Color: name}
+				}
+			}
+			i++
+			continue
+		}
+		text.WriteRune(runes[i])
+	}
+	flush()
+
+	if len(extra) == 1 {
+		return extra[0]
+	}
+	return Component{Extra: extra}
+}
+
+// legacyCodeFor returns the §-code for a vanilla color name, if there is
+// one.
+func legacyCodeFor(color string) (byte, bool) {
+	for code, name := range legacyColors {
+		if name == color {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// FormatLegacy flattens c back into an old-style §-coded string, resolving
+// inherited style as it walks the tree.
+func FormatLegacy(c Component) string {
+	var b strings.Builder
+	writeLegacy(&b, c, nil)
+	return b.String()
+}
+
+func writeLegacy(b *strings.Builder, c Component, parent *Component) {
+	r := c.Resolve(parent)
+
+	if code, ok := legacyCodeFor(r.Color); ok {
+		b.WriteRune(legacyChar)
+		b.WriteByte(code)
+	}
+	for _, f := range []struct {
+		set  OptionalBool
+		code byte
+	}{
+		{r.Bold, 'l'},
+		{r.Italic, 'o'},
+		{r.Underlined, 'n'},
+		{r.Strikethrough, 'm'},
+		{r.Obfuscated, 'k'},
+	} {
+		if f.set == True {
+			b.WriteRune(legacyChar)
+			b.WriteByte(f.code)
+		}
+	}
+
+	b.WriteString(r.Text)
+	for _, e := range r.Extra {
+		writeLegacy(b, e, &r)
+	}
+}
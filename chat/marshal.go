@@ -0,0 +1,27 @@
+package chat
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/NickyBoy89/go-mc/net/packet"
+)
+
+// Marshal and Unmarshal let Component be used directly as a generated
+// packet field: on the wire a text component is its JSON encoding,
+// length-prefixed the same way any other protocol string is.
+func (c Component) Marshal(w io.Writer) error {
+	buf, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return packet.String(buf).Marshal(w)
+}
+
+func (c *Component) Unmarshal(r io.Reader) error {
+	var s packet.String
+	if err := s.Unmarshal(r); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(s), c)
+}
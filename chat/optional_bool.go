@@ -0,0 +1,44 @@
+package chat
+
+// OptionalBool models a vanilla formatting flag, which is tri-state in
+// text-component JSON: true, false, or omitted entirely (meaning "inherit
+// from the parent component"). The zero value, Unset, is what a struct
+// literal or a JSON object missing the key both produce.
+type OptionalBool int8
+
+const (
+	Unset OptionalBool = iota
+	True
+	False
+)
+
+// orElse returns b if it's set, otherwise fallback.
+func (b OptionalBool) orElse(fallback OptionalBool) OptionalBool {
+	if b != Unset {
+		return b
+	}
+	return fallback
+}
+
+func (b OptionalBool) MarshalJSON() ([]byte, error) {
+	switch b {
+	case True:
+		return []byte("true"), nil
+	case False:
+		return []byte("false"), nil
+	default:
+		return []byte("null"), nil
+	}
+}
+
+func (b *OptionalBool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true":
+		*b = True
+	case "false":
+		*b = False
+	default:
+		*b = Unset
+	}
+	return nil
+}
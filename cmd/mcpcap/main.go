@@ -0,0 +1,83 @@
+// Command mcpcap dumps a decoded JSON transcript from a pcapng capture
+// produced by net/capture, for debugging protocol regressions across the
+// multi-version packet tables in data/.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/NickyBoy89/go-mc/data"
+	"github.com/NickyBoy89/go-mc/net/capture"
+)
+
+func main() {
+	proto := flag.Int("proto", 0, "protocol version the capture was recorded under")
+	stateName := flag.String("state", "play", "connection state: handshake, status, login, play, configuration")
+	flag.Parse()
+
+	if flag.NArg() != 1 || *proto == 0 {
+		fmt.Fprintln(os.Stderr, "usage: mcpcap -proto <version> [-state play] <capture.pcapng>")
+		os.Exit(2)
+	}
+
+	state, ok := parseState(*stateName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "mcpcap: unknown state %q\n", *stateName)
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	packets, err := capture.Replay(f, *proto, state)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "mcpcap:", err)
+		os.Exit(1)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	for _, p := range packets {
+		if err := enc.Encode(transcriptLine{
+			Time:      p.Time,
+			Direction: directionName(p.Direction),
+			ID:        int32(p.ID),
+			Name:      p.Name,
+			Packet:    p.Packet,
+		}); err != nil {
+			fmt.Fprintln(os.Stderr, "mcpcap:", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func parseState(s string) (data.ConnState, bool) {
+	switch s {
+	case "handshake", "handshaking":
+		return data.Handshake, true
+	case "status":
+		return data.Status, true
+	case "login":
+		return data.Login, true
+	case "play":
+		return data.Play, true
+	case "configuration":
+		return data.Configuration, true
+	default:
+		return 0, false
+	}
+}
+
+func directionName(d data.Direction) string {
+	if d == data.Serverbound {
+		return "serverbound"
+	}
+	return "clientbound"
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"time"
+
+	"github.com/NickyBoy89/go-mc/data"
+)
+
+// transcriptLine is one JSON object emitted per decoded packet.
+type transcriptLine struct {
+	Time      time.Time   `json:"time"`
+	Direction string      `json:"direction"`
+	ID        int32       `json:"id"`
+	Name      string      `json:"name,omitempty"`
+	Packet    data.Packet `json:"packet,omitempty"`
+}
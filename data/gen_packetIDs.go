@@ -1,23 +1,298 @@
-// gen_packetIDs.go generates the enumeration of packet IDs used on the wire.
+// gen_packetIDs.go generates the enumeration of packet IDs used on the wire,
+// across every Java Edition protocol version published by
+// PrismarineJS/minecraft-data.
 
 //+build ignore
 
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/iancoleman/strcase"
 )
 
 const (
-	protocolURL = "https://raw.githubusercontent.com/PrismarineJS/minecraft-data/master/data/pc/1.16.2/protocol.json"
+	mcDataRawRoot       = "https://raw.githubusercontent.com/PrismarineJS/minecraft-data/master/data/pc"
+	protocolVersionsURL = mcDataRawRoot + "/common/protocolVersions.json"
+	commitAPIURL        = "https://api.github.com/repos/PrismarineJS/minecraft-data/commits/master"
+
+	// modulePath is used to import the runtime support packages the
+	// generated packet structs build on.
+	modulePath = "github.com/NickyBoy89/go-mc"
+
+	// snapshotDir holds the vendored minecraft-data files -refresh writes
+	// and the default (offline) mode reads, relative to this file's
+	// directory (data/).
+	snapshotDir = "../internal/mcdata"
+	// versionsJSONPath records the pinned minecraft-data commit, relative
+	// to this file's directory.
+	versionsJSONPath = "../versions.json"
 )
 
+// protocolJSONURL returns the location of the protocol.json for a given
+// minecraft-data version directory (e.g. "1.16.2").
+func protocolJSONURL(dir string) string {
+	return fmt.Sprintf("%s/%s/protocol.json", mcDataRawRoot, dir)
+}
+
+// ---- offline/online data source ----
+//
+// By default the generator reads a vendored, checksum-verified snapshot of
+// minecraft-data out of internal/mcdata, pinned to the commit recorded in
+// versions.json, so `go generate ./...` works offline and reproducibly.
+// Running with -refresh instead re-downloads everything from GitHub,
+// updates the snapshot and checksums, and records the new commit.
+
+// fetcher is the source of truth gen_packetIDs.go reads minecraft-data
+// files from.
+type fetcher interface {
+	// Get returns the raw bytes of one minecraft-data file, named by its
+	// path relative to data/pc/ (e.g. "common/protocolVersions.json" or
+	// "1.16.2/protocol.json").
+	Get(relPath string) ([]byte, error)
+}
+
+// versionsFile is the repo-root versions.json this generator reads and,
+// with -refresh, rewrites.
+type versionsFile struct {
+	Commit string `json:"commit"`
+}
+
+func readVersionsFile() (versionsFile, error) {
+	buf, err := os.ReadFile(versionsJSONPath)
+	if err != nil {
+		return versionsFile{}, err
+	}
+	var vf versionsFile
+	if err := json.Unmarshal(buf, &vf); err != nil {
+		return versionsFile{}, err
+	}
+	return vf, nil
+}
+
+func writeVersionsFile(vf versionsFile) error {
+	buf, err := json.MarshalIndent(vf, "", "  ")
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+	return os.WriteFile(versionsJSONPath, buf, 0o644)
+}
+
+// checksums maps a minecraft-data file's path (relative to data/pc/) to
+// its hex-encoded SHA-256, recorded in internal/mcdata/checksums.json.
+type checksums map[string]string
+
+func checksumsPath() string { return filepath.Join(snapshotDir, "checksums.json") }
+
+func readChecksums() (checksums, error) {
+	buf, err := os.ReadFile(checksumsPath())
+	if os.IsNotExist(err) {
+		return checksums{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var sums checksums
+	if err := json.Unmarshal(buf, &sums); err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+func writeChecksums(sums checksums) error {
+	buf, err := json.MarshalIndent(sums, "", "  ")
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+	return os.WriteFile(checksumsPath(), buf, 0o644)
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedFetcher reads minecraft-data files out of the vendored
+// internal/mcdata snapshot, refusing to return a file whose contents
+// don't match the recorded checksum.
+type cachedFetcher struct {
+	sums checksums
+}
+
+func newCachedFetcher() (*cachedFetcher, error) {
+	sums, err := readChecksums()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", checksumsPath(), err)
+	}
+	return &cachedFetcher{sums: sums}, nil
+}
+
+func (c *cachedFetcher) Get(relPath string) ([]byte, error) {
+	buf, err := os.ReadFile(filepath.Join(snapshotDir, relPath))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w (run with -refresh to populate the snapshot)", relPath, err)
+	}
+	want, ok := c.sums[relPath]
+	if !ok {
+		return nil, fmt.Errorf("%s: no checksum recorded; run with -refresh", relPath)
+	}
+	if got := sha256Hex(buf); got != want {
+		return nil, fmt.Errorf("%s: checksum mismatch (snapshot and checksums.json have drifted); run with -refresh", relPath)
+	}
+	return buf, nil
+}
+
+// refreshFetcher downloads minecraft-data files live from GitHub, and as
+// a side effect vendors each one into the snapshot and records its
+// checksum, ready for save to persist both to disk.
+type refreshFetcher struct {
+	sums checksums
+}
+
+func newRefreshFetcher() *refreshFetcher {
+	return &refreshFetcher{sums: checksums{}}
+}
+
+func (r *refreshFetcher) Get(relPath string) ([]byte, error) {
+	resp, err := http.Get(mcDataRawRoot + "/" + relPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	full := filepath.Join(snapshotDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(full, buf, 0o644); err != nil {
+		return nil, err
+	}
+	r.sums[relPath] = sha256Hex(buf)
+	return buf, nil
+}
+
+// save persists the checksums collected while fetching, and records the
+// minecraft-data commit this snapshot was pinned to.
+func (r *refreshFetcher) save(commit string) error {
+	if err := writeChecksums(r.sums); err != nil {
+		return err
+	}
+	return writeVersionsFile(versionsFile{Commit: commit})
+}
+
+// fetchLatestCommit returns the SHA of minecraft-data's current master,
+// to pin the snapshot -refresh just downloaded to.
+func fetchLatestCommit() (string, error) {
+	resp, err := http.Get(commitAPIURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", err
+	}
+	if out.SHA == "" {
+		return "", fmt.Errorf("mcdata: commit API response had no sha")
+	}
+	return out.SHA, nil
+}
+
+// openFetcher returns the fetcher -refresh selects, along with the
+// minecraft-data commit its data is (or, after a refresh, now is) pinned
+// to.
+func openFetcher(doRefresh bool) (fetcher, string, error) {
+	if !doRefresh {
+		fe, err := newCachedFetcher()
+		if err != nil {
+			return nil, "", err
+		}
+		vf, err := readVersionsFile()
+		if err != nil {
+			return nil, "", fmt.Errorf("reading %s: %w (run with -refresh to create it)", versionsJSONPath, err)
+		}
+		return fe, vf.Commit, nil
+	}
+
+	commit, err := fetchLatestCommit()
+	if err != nil {
+		return nil, "", err
+	}
+	return newRefreshFetcher(), commit, nil
+}
+
+// connState mirrors data.ConnState, but is only used while generating: it
+// also knows the key minecraft-data uses for the state in protocol.json.
+type connState int
+
+const (
+	handshakeState connState = iota
+	statusState
+	loginState
+	playState
+	configurationState
+)
+
+// allStates lists every connState, in the order a connection passes
+// through them.
+var allStates = []connState{handshakeState, statusState, loginState, playState, configurationState}
+
+// gameState is the key minecraft-data nests a state's packets under.
+func (s connState) gameState() string {
+	switch s {
+	case handshakeState:
+		return "handshaking"
+	case statusState:
+		return "status"
+	case loginState:
+		return "login"
+	case playState:
+		return "play"
+	case configurationState:
+		return "configuration"
+	default:
+		panic("unknown connState")
+	}
+}
+
+// goName is the identifier for this state in the generated data.ConnState.
+func (s connState) goName() string {
+	switch s {
+	case handshakeState:
+		return "Handshake"
+	case statusState:
+		return "Status"
+	case loginState:
+		return "Login"
+	case playState:
+		return "Play"
+	case configurationState:
+		return "Configuration"
+	default:
+		panic("unknown connState")
+	}
+}
+
 // unnest is a utility function to unpack a value from a nested map, given
 // an arbitrary set of keys to reach through.
 func unnest(input map[string]interface{}, keys ...string) (map[string]interface{}, error) {
@@ -38,117 +313,211 @@ func unnest(input map[string]interface{}, keys ...string) (map[string]interface{
 type duplexMappings struct {
 	Clientbound map[string]string
 	Serverbound map[string]string
+
+	// ClientboundRaw/ServerboundRaw map each entry's Go-cased name back to
+	// the snake_case packet name protocol.json itself uses (the key its
+	// field layout is found under, "packet_<rawName>"), which GoName->id
+	// above doesn't preserve.
+	ClientboundRaw map[string]string
+	ServerboundRaw map[string]string
 }
 
 func (m *duplexMappings) EnsureUniqueNames() {
 	// Assemble a slice of keys to check across both maps, because we cannot
 	// mutate a map while iterating it.
 	clientKeys := make([]string, 0, len(m.Clientbound))
-	for k, _ := range m.Clientbound {
+	for k := range m.Clientbound {
 		clientKeys = append(clientKeys, k)
 	}
 
 	for _, k := range clientKeys {
 		if _, alsoServerKey := m.Serverbound[k]; alsoServerKey {
 			cVal, sVal := m.Clientbound[k], m.Serverbound[k]
+			cRaw, sRaw := m.ClientboundRaw[k], m.ServerboundRaw[k]
 			delete(m.Clientbound, k)
 			delete(m.Serverbound, k)
+			delete(m.ClientboundRaw, k)
+			delete(m.ServerboundRaw, k)
 			m.Clientbound[k+"Clientbound"] = cVal
 			m.Serverbound[k+"Serverbound"] = sVal
+			m.ClientboundRaw[k+"Clientbound"] = cRaw
+			m.ServerboundRaw[k+"Serverbound"] = sRaw
 		}
 	}
 }
 
+// packetMappings extracts the packet-ID -> packet-name table out of the
+// "packet" switch type found in a toClient/toServer types block.
+func packetMappings(info map[string]interface{}) map[string]string {
+	pType := info["packet"].([]interface{})[1].([]interface{})[0].(map[string]interface{})["type"]
+	raw := pType.([]interface{})[1].(map[string]interface{})["mappings"].(map[string]interface{})
+	out := make(map[string]string, len(raw))
+	for k, v := range raw {
+		out[k] = v.(string)
+	}
+	return out
+}
+
 // unpackMapping returns the set of packet IDs and their names for a given
-// game state.
-func unpackMapping(data map[string]interface{}, gameState string) (duplexMappings, error) {
+// connState. States that don't exist in data (e.g. "configuration" on old
+// versions, or the half of handshaking with no packets) come back empty
+// rather than erroring, since which states exist varies by version.
+func unpackMapping(data map[string]interface{}, state connState) duplexMappings {
 	out := duplexMappings{
-		Clientbound: make(map[string]string),
-		Serverbound: make(map[string]string),
+		Clientbound:    make(map[string]string),
+		Serverbound:    make(map[string]string),
+		ClientboundRaw: make(map[string]string),
+		ServerboundRaw: make(map[string]string),
 	}
 
-	info, err := unnest(data, gameState, "toClient", "types")
-	if err != nil {
-		return duplexMappings{}, err
-	}
-	pType := info["packet"].([]interface{})[1].([]interface{})[0].(map[string]interface{})["type"]
-	mappings := pType.([]interface{})[1].(map[string]interface{})["mappings"].(map[string]interface{})
-	for k, v := range mappings {
-		out.Clientbound[strcase.ToCamel(v.(string))] = k
-	}
-	info, err = unnest(data, gameState, "toServer", "types")
-	if err != nil {
-		return duplexMappings{}, err
+	if info, err := unnest(data, state.gameState(), "toClient", "types"); err == nil {
+		for k, v := range packetMappings(info) {
+			goName := strcase.ToCamel(v)
+			out.Clientbound[goName] = k
+			out.ClientboundRaw[goName] = v
+		}
 	}
-	pType = info["packet"].([]interface{})[1].([]interface{})[0].(map[string]interface{})["type"]
-	mappings = pType.([]interface{})[1].(map[string]interface{})["mappings"].(map[string]interface{})
-	for k, v := range mappings {
-		out.Serverbound[strcase.ToCamel(v.(string))] = k
+	if info, err := unnest(data, state.gameState(), "toServer", "types"); err == nil {
+		for k, v := range packetMappings(info) {
+			goName := strcase.ToCamel(v)
+			out.Serverbound[goName] = k
+			out.ServerboundRaw[goName] = v
+		}
 	}
 
-	return out, nil
+	out.EnsureUniqueNames()
+	return out
 }
 
-type protocolIDs struct {
-	Login  duplexMappings
-	Play   duplexMappings
-	Status duplexMappings
-	// Handshake state has no packets
-}
+// protocolIDs holds the packet mappings for every non-empty connState of a
+// single protocol version.
+type protocolIDs map[connState]duplexMappings
 
-func (p protocolIDs) MaxLen() int {
-	var max int
-	for _, m := range []duplexMappings{p.Login, p.Play, p.Status} {
-		for k, _ := range m.Clientbound {
-			if len(k) > max {
-				max = len(k)
-			}
-		}
-		for k, _ := range m.Serverbound {
-			if len(k) > max {
-				max = len(k)
-			}
-		}
-	}
-	return max
+type protocolVersionEntry struct {
+	MinecraftVersion string `json:"minecraftVersion"`
+	Version          int    `json:"version"`
+	UsesNetty        bool   `json:"usesNetty"`
 }
 
-func downloadInfo() (*protocolIDs, error) {
-	resp, err := http.Get(protocolURL)
+// fetchProtocolVersions lists every Java protocol version minecraft-data
+// knows about. Pre-Netty versions (1.6 and earlier) use a different framing
+// that this generator doesn't support, so they're filtered out.
+func fetchProtocolVersions(fe fetcher) ([]protocolVersionEntry, error) {
+	buf, err := fe.Get("common/protocolVersions.json")
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	var data map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+
+	var entries []protocolVersionEntry
+	if err := json.Unmarshal(buf, &entries); err != nil {
 		return nil, err
 	}
 
-	var out protocolIDs
-	if out.Login, err = unpackMapping(data, "login"); err != nil {
-		return nil, fmt.Errorf("login: %v", err)
+	netty := entries[:0]
+	for _, e := range entries {
+		if e.UsesNetty {
+			netty = append(netty, e)
+		}
 	}
-	out.Login.EnsureUniqueNames()
-	if out.Play, err = unpackMapping(data, "play"); err != nil {
-		return nil, fmt.Errorf("play: %v", err)
+	return netty, nil
+}
+
+// downloadVersion fetches and unpacks the protocol.json for a single
+// minecraft-data version directory, returning both the ID mappings and the
+// raw decoded document (needed later to translate packet field layouts).
+func downloadVersion(fe fetcher, dir string) (protocolIDs, map[string]interface{}, error) {
+	buf, err := fe.Get(dir + "/protocol.json")
+	if err != nil {
+		return nil, nil, err
 	}
-	out.Play.EnsureUniqueNames()
-	if out.Status, err = unpackMapping(data, "status"); err != nil {
-		return nil, fmt.Errorf("play: %v", err)
+	var data map[string]interface{}
+	if err := json.Unmarshal(buf, &data); err != nil {
+		return nil, nil, err
 	}
-	out.Status.EnsureUniqueNames()
 
-	return &out, nil
+	out := make(protocolIDs, len(allStates))
+	for _, s := range allStates {
+		mapping := unpackMapping(data, s)
+		if len(mapping.Clientbound) == 0 && len(mapping.Serverbound) == 0 {
+			continue
+		}
+		out[s] = mapping
+	}
+	return out, data, nil
 }
 
+// versionRange groups a contiguous run of protocol versions that share an
+// identical set of packet mappings, so the generated table doesn't repeat
+// the same maps once per version.
+type versionRange struct {
+	MinProto int
+	MaxProto int
+	IDs      protocolIDs
+}
+
+// collapseVersions walks versions in increasing protocol-number order and
+// merges adjacent entries whose IDs are identical into a single range.
+func collapseVersions(versions []protocolVersionEntry, ids map[int]protocolIDs) []versionRange {
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+
+	var ranges []versionRange
+	for _, v := range versions {
+		cur, ok := ids[v.Version]
+		if !ok {
+			continue
+		}
+		if n := len(ranges); n > 0 && reflect.DeepEqual(ranges[n-1].IDs, cur) {
+			ranges[n-1].MaxProto = v.Version
+			continue
+		}
+		ranges = append(ranges, versionRange{MinProto: v.Version, MaxProto: v.Version, IDs: cur})
+	}
+	return ranges
+}
+
+var refresh = flag.Bool("refresh", false, "re-download minecraft-data from GitHub and refresh the vendored internal/mcdata snapshot, instead of reading it as-is")
+
 func main() {
-	pIDs, err := downloadInfo()
+	flag.Parse()
+
+	fe, commit, err := openFetcher(*refresh)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	maxLen := pIDs.MaxLen()
+	versions, err := fetchProtocolVersions(fe)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ids := make(map[int]protocolIDs, len(versions))
+	raw := make(map[int]map[string]interface{}, len(versions))
+	for _, v := range versions {
+		pIDs, data, err := downloadVersion(fe, v.MinecraftVersion)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "skipping %s (protocol %d): %v\n", v.MinecraftVersion, v.Version, err)
+			continue
+		}
+		ids[v.Version] = pIDs
+		raw[v.Version] = data
+	}
+
+	ranges := collapseVersions(versions, ids)
+
+	for _, r := range ranges {
+		if err := writePacketStructs(r, raw[r.MinProto]); err != nil {
+			fmt.Fprintf(os.Stderr, "protocol %d-%d: %v\n", r.MinProto, r.MaxProto, err)
+		}
+	}
+
+	if rf, ok := fe.(*refreshFetcher); ok {
+		if err := rf.save(commit); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving snapshot: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	f, err := os.Create("packetIDs.go")
 	if err != nil {
@@ -158,46 +527,481 @@ func main() {
 	defer f.Close()
 
 	fmt.Fprintln(f, "// This file is automatically generated by gen_packetIDs.go. DO NOT EDIT.")
+	fmt.Fprintf(f, "//\n// Generated from PrismarineJS/minecraft-data commit %s.\n", commit)
+	fmt.Fprintln(f, "// Protocol versions covered:")
+	for _, r := range ranges {
+		fmt.Fprintf(f, "//   %d-%d\n", r.MinProto, r.MaxProto)
+	}
 	fmt.Fprintln(f)
 	fmt.Fprintln(f, "package data")
 	fmt.Fprintln(f)
 	fmt.Fprintln(f, "//go:generate go run gen_packetIDs.go")
 	fmt.Fprintln(f)
-	fmt.Fprintln(f, "// PktID represents a packet ID used in the minecraft protocol.")
-	fmt.Fprintln(f, "type PktID int32")
-	fmt.Fprintln(f)
-	fmt.Fprintln(f, "// Valid PktID values.")
-	fmt.Fprintln(f, "const (")
 
-	fmt.Fprintln(f, "  // Clientbound packets for connections in the login state.")
-	for k, v := range pIDs.Login.Clientbound {
-		fmt.Fprintf(f, "  %s%s PktID = %s\n", k, strings.Repeat(" ", maxLen-len(k)), v)
+	fmt.Fprintln(f, "func init() {")
+	fmt.Fprintln(f, "\tpacketVersions = []versionRange{")
+	for _, r := range ranges {
+		fmt.Fprintf(f, "\t\t{\n\t\t\tMin: %d,\n\t\t\tMax: %d,\n\t\t\tStates: map[ConnState]map[Direction]map[string]PktID{\n", r.MinProto, r.MaxProto)
+		for _, s := range allStates {
+			m, ok := r.IDs[s]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(f, "\t\t\t\t%s: {\n", s.goName())
+			writeDirection(f, "Clientbound", m.Clientbound)
+			writeDirection(f, "Serverbound", m.Serverbound)
+			fmt.Fprintln(f, "\t\t\t\t},")
+		}
+		fmt.Fprintln(f, "\t\t\t},")
+		fmt.Fprintln(f, "\t\t},")
 	}
-	fmt.Fprintln(f, "  // Serverbound packets for connections in the login state.")
-	for k, v := range pIDs.Login.Serverbound {
-		fmt.Fprintf(f, "  %s%s PktID = %s\n", k, strings.Repeat(" ", maxLen-len(k)), v)
+	fmt.Fprintln(f, "\t}")
+	fmt.Fprintln(f, "}")
+}
+
+// writeDirection emits one direction's name->PktID map, sorted by name so
+// unrelated changes elsewhere in minecraft-data don't churn this block.
+func writeDirection(f *os.File, goName string, names map[string]string) {
+	fmt.Fprintf(f, "\t\t\t\t\t%s: {\n", goName)
+	for _, name := range sortedKeys(names) {
+		fmt.Fprintf(f, "\t\t\t\t\t\t%q: %s,\n", name, names[name])
 	}
-	fmt.Fprintln(f)
+	fmt.Fprintln(f, "\t\t\t\t\t},")
+}
+
+// sortedKeys returns m's keys in sorted order, so the generated ID table
+// and the order packets are registered in don't depend on Go's randomized
+// map iteration.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ---- packet struct generation ----
+//
+// Beyond the PktID table above, the generator also walks each packet's
+// field layout and emits a Go struct with Marshal/Unmarshal methods, so
+// callers don't need to hand-write a decoder for every packet. Output
+// lands in data/v<MinProto>/packets_<state>.go, one package per collapsed
+// versionRange.
+
+// fieldKind says how a genField's Marshal/Unmarshal call is shaped; plain
+// fields just delegate straight to the field's own Marshal/Unmarshal,
+// while array and option fields need extra framing around that call.
+type fieldKind int
+
+const (
+	kindPlain fieldKind = iota
+	kindArray
+	kindOption
+	kindUnsupported
+)
+
+// genField is one struct field of a generated packet or container.
+type genField struct {
+	Name      string // exported Go field name
+	GoType    string // Go type used to declare the field
+	ElemType  string // for array/option fields, the element's Go type
+	CountType string // for array fields, the Go type of the length prefix
+	Kind      fieldKind
+}
+
+// genStruct is one Go struct the generator will emit: either a packet
+// itself, or a nested type standing in for a "container" field.
+type genStruct struct {
+	Name   string
+	Fields []genField
+}
+
+// primitiveGoType maps a minecraft-data scalar type name to the packet
+// field type that encodes it. Anything not recognized (a state's custom
+// alias, or a type this generator doesn't model yet) falls back to
+// packet.Field, which marks the containing struct as needing a
+// hand-written Marshal/Unmarshal.
+func primitiveGoType(mcType string) (goType string, ok bool) {
+	switch mcType {
+	case "varint":
+		return "packet.VarInt", true
+	case "varlong":
+		return "packet.VarLong", true
+	case "bool":
+		return "packet.Boolean", true
+	case "i8":
+		return "packet.Byte", true
+	case "u8":
+		return "packet.UByte", true
+	case "i16":
+		return "packet.Short", true
+	case "u16":
+		return "packet.UShort", true
+	case "i32":
+		return "packet.Int", true
+	case "i64":
+		return "packet.Long", true
+	case "f32":
+		return "packet.Float", true
+	case "f64":
+		return "packet.Double", true
+	case "UUID":
+		return "packet.UUID", true
+	case "string":
+		return "packet.String", true
+	case "chat":
+		return "chat.Component", true
+	case "position":
+		return "packet.Position", true
+	case "restBuffer":
+		return "packet.RestBuffer", true
+	case "nbt", "optionalNbt":
+		return "packet.NBT", true
+	default:
+		return "packet.Field", false
+	}
+}
+
+// bitfieldGoType approximates a "bitfield" type (several packed sub-fields)
+// as a single integer wide enough to hold all of them (packet.UByte or
+// packet.UShort up to 16 bits, the signed packet.Int or packet.Long beyond
+// that). Splitting
+// it into its logical sub-fields is left to a hand-written accessor.
+func bitfieldGoType(parts []interface{}) string {
+	total := 0
+	for _, p := range parts {
+		if m, ok := p.(map[string]interface{}); ok {
+			if size, ok := m["size"].(float64); ok {
+				total += int(size)
+			}
+		}
+	}
+	switch {
+	case total <= 8:
+		return "packet.UByte"
+	case total <= 16:
+		return "packet.UShort"
+	case total <= 32:
+		return "packet.Int"
+	default:
+		return "packet.Long"
+	}
+}
+
+// plainSwitchGoType handles the "plain (non-recursive)" subset of a switch
+// type: every branch in opts["fields"] (plus opts["default"], if present
+// and not "void") is a scalar type name that resolves to the same Go type
+// via primitiveGoType. compareTo itself is ignored here; callers that care
+// which branch was taken need a hand-written Marshal/Unmarshal. Anything
+// recursive (a branch that's itself an array/container/switch) or branches
+// that disagree on their Go type is left unsupported.
+func plainSwitchGoType(opts map[string]interface{}) (goType string, ok bool) {
+	fields, _ := opts["fields"].(map[string]interface{})
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	branches := make([]string, 0, len(fields)+1)
+	for _, v := range fields {
+		s, ok := v.(string)
+		if !ok {
+			// A nested array/container/switch branch; not part of the
+			// plain subset this handles.
+			return "", false
+		}
+		branches = append(branches, s)
+	}
+	if def, ok := opts["default"].(string); ok && def != "void" {
+		branches = append(branches, def)
+	}
+
+	for _, mcType := range branches {
+		branchType, branchOK := primitiveGoType(mcType)
+		if !branchOK {
+			return "", false
+		}
+		if goType == "" {
+			goType = branchType
+		} else if goType != branchType {
+			return "", false
+		}
+	}
+	return goType, true
+}
+
+// translateField turns one protocol.json field ({"name":..., "type":...})
+// into a genField, appending any nested struct it needs (from a
+// "container" type) to extra.
+func translateField(structPrefix, fieldName string, typ interface{}, extra *[]genStruct) genField {
+	goName := strcase.ToCamel(fieldName)
+
+	switch t := typ.(type) {
+	case string:
+		goType, ok := primitiveGoType(t)
+		if !ok {
+			return genField{Name: goName, GoType: goType, Kind: kindUnsupported}
+		}
+		return genField{Name: goName, GoType: goType, Kind: kindPlain}
+
+	case []interface{}:
+		if len(t) != 2 {
+			return genField{Name: goName, GoType: "packet.Field", Kind: kindUnsupported}
+		}
+		kind, _ := t[0].(string)
+		switch kind {
+		case "array":
+			opts, _ := t[1].(map[string]interface{})
+			if _, ok := opts["count"]; ok {
+				// The element count is read from a sibling field instead of
+				// self-prefixing the array; wiring that up needs a
+				// hand-written Marshal/Unmarshal.
+				return genField{Name: goName, GoType: "packet.Field", Kind: kindUnsupported}
+			}
+			countType := "varint"
+			if ct, ok := opts["countType"].(string); ok {
+				countType = ct
+			}
+			countGoType, ok := primitiveGoType(countType)
+			if !ok {
+				return genField{Name: goName, GoType: "packet.Field", Kind: kindUnsupported}
+			}
+			elem := translateField(structPrefix, fieldName+"Elem", opts["type"], extra)
+			if elem.Kind != kindPlain {
+				return genField{Name: goName, GoType: "packet.Field", Kind: kindUnsupported}
+			}
+			return genField{Name: goName, GoType: "[]" + elem.GoType, ElemType: elem.GoType, CountType: countGoType, Kind: kindArray}
+
+		case "option":
+			elem := translateField(structPrefix, fieldName, t[1], extra)
+			if elem.Kind != kindPlain {
+				return genField{Name: goName, GoType: "packet.Field", Kind: kindUnsupported}
+			}
+			return genField{Name: goName, GoType: "*" + elem.GoType, ElemType: elem.GoType, Kind: kindOption}
+
+		case "container":
+			fields, _ := t[1].([]interface{})
+			nestedName := structPrefix + goName
+			nested := genStruct{Name: nestedName}
+			for _, rf := range fields {
+				rfm, ok := rf.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := rfm["name"].(string)
+				if name == "" {
+					// Anonymous entries (e.g. bitfield padding) carry no
+					// Go-visible value worth keeping.
+					continue
+				}
+				nested.Fields = append(nested.Fields, translateField(nestedName, name, rfm["type"], extra))
+			}
+			*extra = append(*extra, nested)
+			return genField{Name: goName, GoType: nestedName, Kind: kindPlain}
+
+		case "buffer":
+			return genField{Name: goName, GoType: "packet.ByteArray", Kind: kindPlain}
 
-	fmt.Fprintln(f, "  // Clientbound packets for connections in the play state.")
-	for k, v := range pIDs.Play.Clientbound {
-		fmt.Fprintf(f, "  %s%s PktID = %s\n", k, strings.Repeat(" ", maxLen-len(k)), v)
+		case "bitfield":
+			parts, _ := t[1].([]interface{})
+			return genField{Name: goName, GoType: bitfieldGoType(parts), Kind: kindPlain}
+
+		case "switch":
+			opts, _ := t[1].(map[string]interface{})
+			if goType, ok := plainSwitchGoType(opts); ok {
+				return genField{Name: goName, GoType: goType, Kind: kindPlain}
+			}
+			// A recursive (container/array) branch, or branches that don't
+			// all agree on one Go type: leave it for a human to wire up
+			// rather than guessing.
+			return genField{Name: goName, GoType: "packet.Field", Kind: kindUnsupported}
+
+		default:
+			// Anything else this generator doesn't understand yet: leave it
+			// for a human to wire up rather than guessing.
+			return genField{Name: goName, GoType: "packet.Field", Kind: kindUnsupported}
+		}
+
+	default:
+		return genField{Name: goName, GoType: "packet.Field", Kind: kindUnsupported}
 	}
-	fmt.Fprintln(f, "  // Serverbound packets for connections in the play state.")
-	for k, v := range pIDs.Play.Serverbound {
-		fmt.Fprintf(f, "  %s%s PktID = %s\n", k, strings.Repeat(" ", maxLen-len(k)), v)
+}
+
+// packetStructs translates every packet named in mappings (GoName -> id)
+// into its genStruct and any nested containers it needs. rawNames maps
+// each GoName back to the snake_case name protocol.json itself uses, so
+// its field layout can be found under types["packet_<rawName>"].
+func packetStructs(types map[string]interface{}, mappings, rawNames map[string]string) []genStruct {
+	// Sorted purely so errors/output are stable to read; content ordering
+	// doesn't otherwise matter here.
+	goNames := make([]string, 0, len(mappings))
+	for goName := range mappings {
+		goNames = append(goNames, goName)
 	}
+	sort.Strings(goNames)
+
+	var out []genStruct
+	for _, goName := range goNames {
+		fieldsType, ok := types["packet_"+rawNames[goName]]
+		fields, _ := fieldsType.([]interface{})
+		main := genStruct{Name: goName}
+		if ok && len(fields) == 2 {
+			if body, ok := fields[1].([]interface{}); ok {
+				var extra []genStruct
+				for _, rf := range body {
+					rfm, ok := rf.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					name, _ := rfm["name"].(string)
+					if name == "" {
+						continue
+					}
+					main.Fields = append(main.Fields, translateField(goName, name, rfm["type"], &extra))
+				}
+				out = append(out, extra...)
+			}
+		}
+		out = append(out, main)
+	}
+	return out
+}
+
+// writeStruct emits one genStruct's type declaration plus its
+// Marshal/Unmarshal methods.
+func writeStruct(f *os.File, s genStruct) {
+	fmt.Fprintf(f, "type %s struct {\n", s.Name)
+	for _, field := range s.Fields {
+		fmt.Fprintf(f, "\t%s %s\n", field.Name, field.GoType)
+	}
+	fmt.Fprintln(f, "}")
 	fmt.Fprintln(f)
 
-	fmt.Fprintln(f, "  // Clientbound packets used to respond to ping/status requests.")
-	for k, v := range pIDs.Status.Clientbound {
-		fmt.Fprintf(f, "  %s%s PktID = %s\n", k, strings.Repeat(" ", maxLen-len(k)), v)
+	unsupported := false
+	for _, field := range s.Fields {
+		if field.Kind == kindUnsupported {
+			unsupported = true
+		}
 	}
-	fmt.Fprintln(f, "  // Serverbound packets used to ping or read server status.")
-	for k, v := range pIDs.Status.Serverbound {
-		fmt.Fprintf(f, "  %s%s PktID = %s\n", k, strings.Repeat(" ", maxLen-len(k)), v)
+
+	fmt.Fprintf(f, "func (p *%s) Marshal(w io.Writer) error {\n", s.Name)
+	if unsupported {
+		fmt.Fprintln(f, "\treturn packet.ErrUnsupportedField")
+	} else {
+		for _, field := range s.Fields {
+			switch field.Kind {
+			case kindPlain:
+				fmt.Fprintf(f, "\tif err := p.%s.Marshal(w); err != nil {\n\t\treturn err\n\t}\n", field.Name)
+			case kindArray:
+				fmt.Fprintf(f, "\tif err := %s(len(p.%s)).Marshal(w); err != nil {\n\t\treturn err\n\t}\n", field.CountType, field.Name)
+				fmt.Fprintf(f, "\tfor i := range p.%s {\n\t\tif err := p.%s[i].Marshal(w); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", field.Name, field.Name)
+			case kindOption:
+				fmt.Fprintf(f, "\tif err := packet.Boolean(p.%s != nil).Marshal(w); err != nil {\n\t\treturn err\n\t}\n", field.Name)
+				fmt.Fprintf(f, "\tif p.%s != nil {\n\t\tif err := p.%s.Marshal(w); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", field.Name, field.Name)
+			}
+		}
+		fmt.Fprintln(f, "\treturn nil")
 	}
+	fmt.Fprintln(f, "}")
 	fmt.Fprintln(f)
 
-	fmt.Fprintln(f, ")")
-}
\ No newline at end of file
+	fmt.Fprintf(f, "func (p *%s) Unmarshal(r io.Reader) error {\n", s.Name)
+	if unsupported {
+		fmt.Fprintln(f, "\treturn packet.ErrUnsupportedField")
+	} else {
+		for _, field := range s.Fields {
+			switch field.Kind {
+			case kindPlain:
+				fmt.Fprintf(f, "\tif err := p.%s.Unmarshal(r); err != nil {\n\t\treturn err\n\t}\n", field.Name)
+			case kindArray:
+				fmt.Fprintf(f, "\tvar %sCount %s\n", field.Name, field.CountType)
+				fmt.Fprintf(f, "\tif err := %sCount.Unmarshal(r); err != nil {\n\t\treturn err\n\t}\n", field.Name)
+				fmt.Fprintf(f, "\tp.%s = make(%s, %sCount)\n", field.Name, field.GoType, field.Name)
+				fmt.Fprintf(f, "\tfor i := range p.%s {\n\t\tif err := p.%s[i].Unmarshal(r); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", field.Name, field.Name)
+			case kindOption:
+				fmt.Fprintln(f, "\tvar present packet.Boolean")
+				fmt.Fprintln(f, "\tif err := present.Unmarshal(r); err != nil {\n\t\treturn err\n\t}")
+				fmt.Fprintf(f, "\tif present {\n\t\tp.%s = new(%s)\n\t\tif err := p.%s.Unmarshal(r); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", field.Name, field.ElemType, field.Name)
+			}
+		}
+		fmt.Fprintln(f, "\treturn nil")
+	}
+	fmt.Fprintln(f, "}")
+}
+
+// writePacketStructs emits data/v<r.MinProto>/packets_<state>.go for every
+// state in r that has packets, using data (the representative version's
+// decoded protocol.json) for field layouts.
+func writePacketStructs(r versionRange, data map[string]interface{}) error {
+	if data == nil {
+		return fmt.Errorf("no protocol.json cached for representative version %d", r.MinProto)
+	}
+	dir := fmt.Sprintf("v%d", r.MinProto)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, s := range allStates {
+		m, ok := r.IDs[s]
+		if !ok {
+			continue
+		}
+		types, err := unnest(data, s.gameState())
+		if err != nil {
+			return fmt.Errorf("%s: %v", s.gameState(), err)
+		}
+		clientTypes, _ := unnest(types, "toClient", "types")
+		serverTypes, _ := unnest(types, "toServer", "types")
+
+		var structs []genStruct
+		structs = append(structs, packetStructs(clientTypes, m.Clientbound, m.ClientboundRaw)...)
+		structs = append(structs, packetStructs(serverTypes, m.Serverbound, m.ServerboundRaw)...)
+
+		path := filepath.Join(dir, fmt.Sprintf("packets_%s.go", s.gameState()))
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+
+		needsChat := false
+		for _, st := range structs {
+			for _, field := range st.Fields {
+				if strings.Contains(field.GoType, "chat.Component") {
+					needsChat = true
+				}
+			}
+		}
+
+		fmt.Fprintf(f, "// Code generated by gen_packetIDs.go for protocol %d-%d. DO NOT EDIT.\n\n", r.MinProto, r.MaxProto)
+		fmt.Fprintf(f, "package v%d\n\n", r.MinProto)
+		fmt.Fprintln(f, "import (")
+		fmt.Fprintln(f, "\t\"io\"")
+		fmt.Fprintln(f)
+		if needsChat {
+			fmt.Fprintf(f, "\t%q\n", modulePath+"/chat")
+		}
+		fmt.Fprintf(f, "\t%q\n", modulePath+"/data")
+		fmt.Fprintf(f, "\t%q\n", modulePath+"/net/packet")
+		fmt.Fprintln(f, ")")
+		fmt.Fprintln(f)
+
+		for _, st := range structs {
+			writeStruct(f, st)
+			fmt.Fprintln(f)
+		}
+
+		fmt.Fprintln(f, "func init() {")
+		for proto := r.MinProto; proto <= r.MaxProto; proto++ {
+			for _, name := range sortedKeys(m.Clientbound) {
+				fmt.Fprintf(f, "\tdata.RegisterPacket(%d, data.%s, data.Clientbound, %s, &%s{})\n", proto, s.goName(), m.Clientbound[name], name)
+			}
+			for _, name := range sortedKeys(m.Serverbound) {
+				fmt.Fprintf(f, "\tdata.RegisterPacket(%d, data.%s, data.Serverbound, %s, &%s{})\n", proto, s.goName(), m.Serverbound[name], name)
+			}
+		}
+		fmt.Fprintln(f, "}")
+
+		f.Close()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package data
+
+import (
+	"sort"
+	"sync"
+)
+
+// PktID is a packet ID as sent on the wire, scoped to a particular protocol
+// version, ConnState and Direction.
+type PktID int32
+
+// ConnState is a stage of the handshake/login/play lifecycle a connection
+// can be in. The packet ID a given packet name maps to depends on both the
+// protocol version and the ConnState the connection is currently in.
+type ConnState int
+
+const (
+	Handshake ConnState = iota
+	Status
+	Login
+	Play
+	Configuration
+)
+
+// Direction describes which end of the connection a packet travels to.
+type Direction int
+
+const (
+	Clientbound Direction = iota
+	Serverbound
+)
+
+// versionRange is a contiguous span of protocol versions ([Min, Max]) that
+// share an identical packet layout. gen_packetIDs.go collapses adjacent
+// versions with identical mappings into a single range so the table stays
+// small even though minecraft-data ships one protocol.json per version.
+type versionRange struct {
+	Min, Max int
+	States   map[ConnState]map[Direction]map[string]PktID
+
+	reverseOnce sync.Once
+	reverse     map[ConnState]map[Direction]map[PktID]string
+}
+
+// packetVersions is populated by the generated packetIDs.go.
+var packetVersions []versionRange
+
+// LookupPktID returns the PktID a server/client uses for the packet named
+// name, under protocol version protoVer, in the given state and direction.
+// It reports false if protoVer, state, direction or name aren't known.
+func LookupPktID(protoVer int, state ConnState, dir Direction, name string) (PktID, bool) {
+	r := findRange(protoVer)
+	if r == nil {
+		return 0, false
+	}
+	id, ok := r.States[state][dir][name]
+	return id, ok
+}
+
+// NameOf is the inverse of LookupPktID: it returns the packet name
+// registered to id, or "" if it isn't known.
+func NameOf(protoVer int, state ConnState, dir Direction, id PktID) string {
+	r := findRange(protoVer)
+	if r == nil {
+		return ""
+	}
+	return r.reverseFor(state, dir)[id]
+}
+
+// reverseFor lazily builds and caches the id->name index for state/dir, so
+// the generated data only ever stores the name->id direction.
+func (r *versionRange) reverseFor(state ConnState, dir Direction) map[PktID]string {
+	r.reverseOnce.Do(func() {
+		r.reverse = make(map[ConnState]map[Direction]map[PktID]string, len(r.States))
+		for s, dirs := range r.States {
+			byDir := make(map[Direction]map[PktID]string, len(dirs))
+			for d, names := range dirs {
+				byID := make(map[PktID]string, len(names))
+				for name, id := range names {
+					byID[id] = name
+				}
+				byDir[d] = byID
+			}
+			r.reverse[s] = byDir
+		}
+	})
+	return r.reverse[state][dir]
+}
+
+// findRange returns the versionRange covering protoVer, or nil if no
+// generated range covers it.
+func findRange(protoVer int) *versionRange {
+	i := sort.Search(len(packetVersions), func(i int) bool {
+		return packetVersions[i].Max >= protoVer
+	})
+	if i == len(packetVersions) || packetVersions[i].Min > protoVer {
+		return nil
+	}
+	return &packetVersions[i]
+}
@@ -0,0 +1,83 @@
+package data
+
+import "testing"
+
+func withTestVersions(t *testing.T, ranges []versionRange) {
+	t.Helper()
+	old := packetVersions
+	packetVersions = ranges
+	t.Cleanup(func() { packetVersions = old })
+}
+
+func testRanges() []versionRange {
+	return []versionRange{
+		{
+			Min: 754, Max: 755,
+			States: map[ConnState]map[Direction]map[string]PktID{
+				Play: {
+					Clientbound: {"KeepAlive": 0x1F, "ChatMessage": 0x0E},
+				},
+			},
+		},
+		{
+			Min: 756, Max: 756,
+			States: map[ConnState]map[Direction]map[string]PktID{
+				Play: {
+					Clientbound: {"KeepAlive": 0x20},
+				},
+			},
+		},
+	}
+}
+
+func TestLookupPktID(t *testing.T) {
+	withTestVersions(t, testRanges())
+
+	id, ok := LookupPktID(755, Play, Clientbound, "KeepAlive")
+	if !ok || id != 0x1F {
+		t.Errorf("LookupPktID(755, ...): got (%v, %v), want (0x1F, true)", id, ok)
+	}
+
+	if _, ok := LookupPktID(999, Play, Clientbound, "KeepAlive"); ok {
+		t.Error("LookupPktID with an unknown protocol version: got ok, want not found")
+	}
+
+	if _, ok := LookupPktID(755, Play, Clientbound, "Nonexistent"); ok {
+		t.Error("LookupPktID with an unknown packet name: got ok, want not found")
+	}
+}
+
+func TestNameOf(t *testing.T) {
+	withTestVersions(t, testRanges())
+
+	if name := NameOf(755, Play, Clientbound, 0x1F); name != "KeepAlive" {
+		t.Errorf("NameOf(755, ..., 0x1F) = %q, want %q", name, "KeepAlive")
+	}
+	// Collapsed ranges keep their own mappings: protocol 756 renumbered
+	// KeepAlive to 0x20, so 0x1F shouldn't resolve there.
+	if name := NameOf(756, Play, Clientbound, 0x1F); name != "" {
+		t.Errorf("NameOf(756, ..., 0x1F) = %q, want \"\"", name)
+	}
+	if name := NameOf(756, Play, Clientbound, 0x20); name != "KeepAlive" {
+		t.Errorf("NameOf(756, ..., 0x20) = %q, want %q", name, "KeepAlive")
+	}
+}
+
+// TestNameOfConcurrent exercises reverseFor's lazily-built cache from many
+// goroutines at once, under the race detector (go test -race). A racy
+// first build previously corrupted or fatally crashed on a concurrent map
+// write here.
+func TestNameOfConcurrent(t *testing.T) {
+	withTestVersions(t, testRanges())
+
+	done := make(chan struct{})
+	for i := 0; i < 32; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			NameOf(755, Play, Clientbound, 0x1F)
+		}()
+	}
+	for i := 0; i < 32; i++ {
+		<-done
+	}
+}
@@ -0,0 +1,43 @@
+package data
+
+import (
+	"io"
+	"reflect"
+)
+
+// Packet is implemented by every generated protocol packet type in the
+// data/v<ver> subpackages.
+type Packet interface {
+	Marshal(w io.Writer) error
+	Unmarshal(r io.Reader) error
+}
+
+// registryKey identifies a packet type by the protocol version, state,
+// direction and PktID it's registered under.
+type registryKey struct {
+	Proto int
+	State ConnState
+	Dir   Direction
+	ID    PktID
+}
+
+// registry is populated by the generated per-version packet files, each of
+// which calls RegisterPacket from an init().
+var registry = map[registryKey]reflect.Type{}
+
+// RegisterPacket associates a concrete Packet type with the protocol
+// version, state, direction and ID it's encoded as on the wire.
+func RegisterPacket(proto int, state ConnState, dir Direction, id PktID, p Packet) {
+	registry[registryKey{proto, state, dir, id}] = reflect.TypeOf(p).Elem()
+}
+
+// PacketType looks up the concrete Packet type registered for a packet, so
+// callers can decode arbitrary packets without hand-written glue:
+//
+//	t, ok := data.PacketType(757, data.Play, data.Clientbound, id)
+//	p := reflect.New(t).Interface().(data.Packet)
+//	err := p.Unmarshal(r)
+func PacketType(proto int, state ConnState, dir Direction, id PktID) (reflect.Type, bool) {
+	t, ok := registry[registryKey{proto, state, dir, id}]
+	return t, ok
+}
@@ -0,0 +1,23 @@
+// Package capture records and replays Minecraft packet traffic as pcapng
+// files, synthesizing Ethernet/IPv4/TCP headers around each framed packet
+// so general-purpose tools (Wireshark, tshark, tcpdump -r) can open a
+// capture directly. It's meant to sit after decryption and decompression,
+// so every recorded frame is plaintext: a varint packet ID followed by
+// that packet's fields, exactly as data.Packet.Marshal would produce.
+package capture
+
+// clientIP, serverIP, clientPort and serverPort are the synthesized
+// addresses used to tell the two directions of a capture apart, both when
+// writing and when replaying it.
+var (
+	clientIP = [4]byte{10, 0, 0, 1}
+	serverIP = [4]byte{10, 0, 0, 2}
+
+	clientMAC = [6]byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+	serverMAC = [6]byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x02}
+)
+
+const (
+	clientPort uint16 = 50000
+	serverPort uint16 = 25565
+)
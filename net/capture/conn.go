@@ -0,0 +1,67 @@
+package capture
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"time"
+
+	"github.com/NickyBoy89/go-mc/data"
+	"github.com/NickyBoy89/go-mc/net/packet"
+)
+
+// Conn wraps a net.Conn that already speaks plaintext, decompressed
+// Minecraft framing (each packet is a varint length prefix followed by a
+// varint packet ID and its fields), and tees every whole packet it sees
+// into a pcapng capture via Tap. It must wrap a connection after
+// decryption and decompression, so the capture always holds plaintext
+// framed packets regardless of what the wire actually carried.
+type Conn struct {
+	net.Conn
+	r        *bufio.Reader
+	tap      *Writer
+	readDir  data.Direction
+	writeDir data.Direction
+}
+
+// Tap wraps conn so every packet ReadPacket/WritePacket sees is also
+// recorded into tap. readDir/writeDir say which direction ReadPacket and
+// WritePacket each represent; a proxy's client-facing leg would use
+// (Serverbound, Clientbound), and its server-facing leg the reverse.
+func Tap(conn net.Conn, tap *Writer, readDir, writeDir data.Direction) *Conn {
+	return &Conn{Conn: conn, r: bufio.NewReader(conn), tap: tap, readDir: readDir, writeDir: writeDir}
+}
+
+// ReadPacket reads one length-prefixed packet and returns its contents
+// (packet ID varint followed by fields), recording it into the tap.
+func (c *Conn) ReadPacket() ([]byte, error) {
+	var length packet.VarInt
+	if err := length.Unmarshal(c.r); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(c.r, buf); err != nil {
+		return nil, err
+	}
+	if c.tap != nil {
+		if err := c.tap.WritePacket(c.readDir, buf, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// WritePacket writes a length prefix followed by frame (packet ID varint
+// + fields) to the underlying connection, recording it into the tap.
+func (c *Conn) WritePacket(frame []byte) error {
+	if err := (packet.VarInt(len(frame))).Marshal(c.Conn); err != nil {
+		return err
+	}
+	if _, err := c.Conn.Write(frame); err != nil {
+		return err
+	}
+	if c.tap != nil {
+		return c.tap.WritePacket(c.writeDir, frame, time.Now())
+	}
+	return nil
+}
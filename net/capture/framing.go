@@ -0,0 +1,97 @@
+package capture
+
+import (
+	"encoding/binary"
+
+	"github.com/NickyBoy89/go-mc/data"
+)
+
+// buildEthernetFrame wraps mcFrame (a varint packet ID followed by its
+// fields) in synthesized Ethernet, IPv4 and TCP headers, so the result is
+// a standalone frame suitable for a pcapng Enhanced Packet Block. seq is
+// the running byte sequence number for dir, and is advanced by the
+// caller.
+func buildEthernetFrame(dir data.Direction, seq uint32, mcFrame []byte) []byte {
+	srcIP, dstIP := serverIP, clientIP
+	srcPort, dstPort := serverPort, clientPort
+	srcMAC, dstMAC := serverMAC, clientMAC
+	if dir == data.Serverbound {
+		srcIP, dstIP = clientIP, serverIP
+		srcPort, dstPort = clientPort, serverPort
+		srcMAC, dstMAC = clientMAC, serverMAC
+	}
+
+	tcp := buildTCPSegment(srcIP, dstIP, srcPort, dstPort, seq, mcFrame)
+	ip := buildIPv4Packet(srcIP, dstIP, tcp)
+
+	frame := make([]byte, 0, 14+len(ip))
+	frame = append(frame, dstMAC[:]...)
+	frame = append(frame, srcMAC[:]...)
+	frame = append(frame, 0x08, 0x00) // EtherType: IPv4
+	frame = append(frame, ip...)
+	return frame
+}
+
+// buildIPv4Packet wraps payload (a full TCP segment) in a minimal, 20-byte
+// IPv4 header.
+func buildIPv4Packet(src, dst [4]byte, payload []byte) []byte {
+	total := 20 + len(payload)
+	h := make([]byte, 20)
+	h[0] = 0x45 // version 4, IHL 5 (20 bytes)
+	h[1] = 0x00 // DSCP/ECN
+	binary.BigEndian.PutUint16(h[2:4], uint16(total))
+	binary.BigEndian.PutUint16(h[4:6], 0) // identification
+	binary.BigEndian.PutUint16(h[6:8], 0) // flags/fragment offset
+	h[8] = 64                             // TTL
+	h[9] = 6                              // protocol: TCP
+	binary.BigEndian.PutUint16(h[10:12], 0)
+	copy(h[12:16], src[:])
+	copy(h[16:20], dst[:])
+	binary.BigEndian.PutUint16(h[10:12], internetChecksum(h))
+
+	return append(h, payload...)
+}
+
+// buildTCPSegment wraps payload in a minimal, 20-byte TCP header (no
+// options), with a correct checksum over the TCP pseudo-header.
+func buildTCPSegment(srcIP, dstIP [4]byte, srcPort, dstPort uint16, seq uint32, payload []byte) []byte {
+	h := make([]byte, 20)
+	binary.BigEndian.PutUint16(h[0:2], srcPort)
+	binary.BigEndian.PutUint16(h[2:4], dstPort)
+	binary.BigEndian.PutUint32(h[4:8], seq)
+	binary.BigEndian.PutUint32(h[8:12], 0) // ack number, unused
+	h[12] = 5 << 4                         // data offset: 5 words (20 bytes), no options
+	h[13] = 0x18                           // flags: PSH, ACK
+	binary.BigEndian.PutUint16(h[14:16], 65535)
+	binary.BigEndian.PutUint16(h[16:18], 0) // checksum, filled below
+	binary.BigEndian.PutUint16(h[18:20], 0) // urgent pointer
+
+	seg := append(h, payload...)
+
+	pseudo := make([]byte, 0, 12+len(seg))
+	pseudo = append(pseudo, srcIP[:]...)
+	pseudo = append(pseudo, dstIP[:]...)
+	pseudo = append(pseudo, 0, 6) // zero, protocol TCP
+	pseudo = binary.BigEndian.AppendUint16(pseudo, uint16(len(seg)))
+	pseudo = append(pseudo, seg...)
+
+	binary.BigEndian.PutUint16(seg[16:18], internetChecksum(pseudo))
+	return seg
+}
+
+// internetChecksum is the ones'-complement checksum used by IPv4, TCP and
+// UDP (RFC 1071).
+func internetChecksum(b []byte) uint16 {
+	var sum uint32
+	n := len(b)
+	for i := 0; i+1 < n; i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if n%2 == 1 {
+		sum += uint32(b[n-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xFFFF + sum>>16
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,35 @@
+package capture
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NickyBoy89/go-mc/data"
+)
+
+func TestInternetChecksumValidatesItself(t *testing.T) {
+	// A correctly checksummed buffer sums to zero under the same algorithm
+	// (the standard self-check for a ones'-complement checksum).
+	h := buildIPv4Packet(clientIP, serverIP, []byte("hello"))
+	if sum := internetChecksum(h[:20]); sum != 0 {
+		t.Errorf("internetChecksum of a valid IPv4 header = %#x, want 0", sum)
+	}
+}
+
+func TestBuildEthernetFrameRoundTrip(t *testing.T) {
+	for _, dir := range []data.Direction{data.Clientbound, data.Serverbound} {
+		mcFrame := []byte{0x00, 'p', 'i', 'n', 'g'}
+		frame := buildEthernetFrame(dir, 1, mcFrame)
+
+		gotDir, gotPayload, err := parseEthernetFrame(frame)
+		if err != nil {
+			t.Fatalf("parseEthernetFrame: %v", err)
+		}
+		if gotDir != dir {
+			t.Errorf("direction: got %v, want %v", gotDir, dir)
+		}
+		if !bytes.Equal(gotPayload, mcFrame) {
+			t.Errorf("payload: got %v, want %v", gotPayload, mcFrame)
+		}
+	}
+}
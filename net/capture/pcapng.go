@@ -0,0 +1,117 @@
+package capture
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Block types and the byte-order magic pcapng uses to self-describe its
+// endianness; this package only ever writes and reads little-endian.
+const (
+	blockTypeSectionHeader  uint32 = 0x0A0D0D0A
+	blockTypeInterfaceDesc  uint32 = 0x00000001
+	blockTypeEnhancedPacket uint32 = 0x00000006
+	byteOrderMagic          uint32 = 0x1A2B3C4D
+
+	linkTypeEthernet uint16 = 1
+)
+
+// writeSectionHeader writes a pcapng Section Header Block declaring
+// little-endian byte order and an unknown section length.
+func writeSectionHeader(w io.Writer) error {
+	body := appendU32(nil, byteOrderMagic)
+	body = appendU16(body, 1) // major version
+	body = appendU16(body, 0) // minor version
+	body = appendU64(body, 0xFFFFFFFFFFFFFFFF)
+	return writeBlock(w, blockTypeSectionHeader, body)
+}
+
+// writeInterfaceDescription writes a pcapng Interface Description Block
+// for a single interface using linkType, with an unlimited snap length.
+func writeInterfaceDescription(w io.Writer, linkType uint16) error {
+	body := appendU16(nil, linkType)
+	body = appendU16(body, 0) // reserved
+	body = appendU32(body, 0) // snap length: 0 means no limit
+	return writeBlock(w, blockTypeInterfaceDesc, body)
+}
+
+// writeEnhancedPacketBlock writes one captured frame, timestamped at ts
+// and attributed to interface ifaceID.
+func writeEnhancedPacketBlock(w io.Writer, ifaceID uint32, ts time.Time, frame []byte) error {
+	micros := uint64(ts.UnixMicro())
+	body := appendU32(nil, ifaceID)
+	body = appendU32(body, uint32(micros>>32))
+	body = appendU32(body, uint32(micros))
+	body = appendU32(body, uint32(len(frame)))
+	body = appendU32(body, uint32(len(frame)))
+	body = append(body, frame...)
+	body = append(body, make([]byte, pad4(len(frame)))...)
+	return writeBlock(w, blockTypeEnhancedPacket, body)
+}
+
+// writeBlock frames body with a pcapng block header and trailer: the
+// block type, the total block length, body, and the total length again.
+func writeBlock(w io.Writer, blockType uint32, body []byte) error {
+	total := uint32(12 + len(body))
+	header := appendU32(nil, blockType)
+	header = appendU32(header, total)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	_, err := w.Write(appendU32(nil, total))
+	return err
+}
+
+// readBlock reads one pcapng block and returns its type and body (the
+// bytes between the two length fields).
+func readBlock(r io.Reader) (blockType uint32, body []byte, err error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	blockType = binary.LittleEndian.Uint32(header[0:4])
+	total := binary.LittleEndian.Uint32(header[4:8])
+	if total < 12 {
+		return 0, nil, fmt.Errorf("capture: implausible block length %d", total)
+	}
+	rest := make([]byte, total-8)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return 0, nil, err
+	}
+	body = rest[:len(rest)-4]
+	trailer := binary.LittleEndian.Uint32(rest[len(rest)-4:])
+	if trailer != total {
+		return 0, nil, fmt.Errorf("capture: block length mismatch (%d vs trailer %d)", total, trailer)
+	}
+	return blockType, body, nil
+}
+
+func pad4(n int) int {
+	if r := n % 4; r != 0 {
+		return 4 - r
+	}
+	return 0
+}
+
+func appendU16(b []byte, v uint16) []byte {
+	buf := make([]byte, 2)
+	binary.LittleEndian.PutUint16(buf, v)
+	return append(b, buf...)
+}
+
+func appendU32(b []byte, v uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, v)
+	return append(b, buf...)
+}
+
+func appendU64(b []byte, v uint64) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, v)
+	return append(b, buf...)
+}
@@ -0,0 +1,117 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/NickyBoy89/go-mc/data"
+	"github.com/NickyBoy89/go-mc/net/packet"
+)
+
+// DecodedPacket is one packet recovered from a capture, in the order it
+// appeared in the original conversation, with its original timestamp.
+type DecodedPacket struct {
+	Time      time.Time
+	Direction data.Direction
+	ID        data.PktID
+	Name      string
+	Packet    data.Packet // nil if the registry has no type for ID under protoVer/state
+}
+
+// Replay reads every packet recorded in a pcapng capture written by
+// Writer/Conn, decoding each one through the data package's registry.
+// protoVer and state must be supplied because a capture alone can't
+// recover which protocol version or connection state it was recorded
+// under.
+func Replay(r io.Reader, protoVer int, state data.ConnState) ([]DecodedPacket, error) {
+	var out []DecodedPacket
+	for {
+		blockType, body, err := readBlock(r)
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return out, err
+		}
+		if blockType != blockTypeEnhancedPacket {
+			continue
+		}
+
+		dp, err := decodeEnhancedPacketBlock(body, protoVer, state)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, dp)
+	}
+}
+
+// decodeEnhancedPacketBlock pulls the Minecraft frame back out of one
+// Enhanced Packet Block's synthesized Ethernet/IPv4/TCP headers and
+// decodes it.
+func decodeEnhancedPacketBlock(body []byte, protoVer int, state data.ConnState) (DecodedPacket, error) {
+	if len(body) < 20 {
+		return DecodedPacket{}, fmt.Errorf("capture: enhanced packet block too short")
+	}
+	tsHigh := binary.LittleEndian.Uint32(body[4:8])
+	tsLow := binary.LittleEndian.Uint32(body[8:12])
+	capLen := binary.LittleEndian.Uint32(body[12:16])
+	ts := time.UnixMicro(int64(tsHigh)<<32 | int64(tsLow))
+
+	if 20+int(capLen) > len(body) {
+		return DecodedPacket{}, fmt.Errorf("capture: enhanced packet block claims a %d-byte capture, but only %d bytes follow the header", capLen, len(body)-20)
+	}
+	eth := body[20 : 20+int(capLen)]
+	dir, frame, err := parseEthernetFrame(eth)
+	if err != nil {
+		return DecodedPacket{}, err
+	}
+
+	var id packet.VarInt
+	br := bytes.NewReader(frame)
+	if err := id.Unmarshal(br); err != nil {
+		return DecodedPacket{}, fmt.Errorf("capture: reading packet ID: %w", err)
+	}
+
+	dp := DecodedPacket{Time: ts, Direction: dir, ID: data.PktID(id)}
+	dp.Name = data.NameOf(protoVer, state, dir, dp.ID)
+
+	if t, ok := data.PacketType(protoVer, state, dir, dp.ID); ok {
+		p := reflect.New(t).Interface().(data.Packet)
+		if err := p.Unmarshal(br); err != nil {
+			return DecodedPacket{}, fmt.Errorf("capture: decoding packet %d: %w", dp.ID, err)
+		}
+		dp.Packet = p
+	}
+	return dp, nil
+}
+
+// parseEthernetFrame strips the synthesized Ethernet/IPv4/TCP headers
+// this package writes and returns the direction (determined by which
+// side's port the segment came from) and the Minecraft frame carried as
+// the TCP payload.
+func parseEthernetFrame(eth []byte) (data.Direction, []byte, error) {
+	if len(eth) < 14+20 {
+		return 0, nil, fmt.Errorf("capture: frame too short")
+	}
+	ip := eth[14:]
+	ihl := int(ip[0]&0x0F) * 4
+	if len(ip) < ihl+20 {
+		return 0, nil, fmt.Errorf("capture: IPv4 packet too short")
+	}
+	tcp := ip[ihl:]
+	srcPort := binary.BigEndian.Uint16(tcp[0:2])
+	dataOffset := int(tcp[12]>>4) * 4
+	if len(tcp) < dataOffset {
+		return 0, nil, fmt.Errorf("capture: TCP segment too short")
+	}
+
+	dir := data.Clientbound
+	if srcPort == clientPort {
+		dir = data.Serverbound
+	}
+	return dir, tcp[dataOffset:], nil
+}
@@ -0,0 +1,19 @@
+package capture
+
+import (
+	"testing"
+
+	"github.com/NickyBoy89/go-mc/data"
+)
+
+func TestDecodeEnhancedPacketBlockRejectsTruncatedCapLen(t *testing.T) {
+	body := appendU32(nil, 0)    // interface ID
+	body = appendU32(body, 0)    // timestamp high
+	body = appendU32(body, 0)    // timestamp low
+	body = appendU32(body, 9999) // capLen, far larger than the body actually holds
+	body = appendU32(body, 9999) // origLen
+
+	if _, err := decodeEnhancedPacketBlock(body, 754, data.Play); err == nil {
+		t.Fatal("decodeEnhancedPacketBlock with a capLen past the end of body: got nil error, want an error")
+	}
+}
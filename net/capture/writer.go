@@ -0,0 +1,39 @@
+package capture
+
+import (
+	"io"
+	"time"
+
+	"github.com/NickyBoy89/go-mc/data"
+)
+
+// Writer records framed Minecraft packets into a pcapng file, one
+// Enhanced Packet Block per packet.
+type Writer struct {
+	w   io.Writer
+	seq map[data.Direction]uint32
+}
+
+// NewWriter writes a pcapng section header and a single Ethernet
+// interface description to w, and returns a Writer ready to record
+// packets onto it.
+func NewWriter(w io.Writer) (*Writer, error) {
+	if err := writeSectionHeader(w); err != nil {
+		return nil, err
+	}
+	if err := writeInterfaceDescription(w, linkTypeEthernet); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, seq: make(map[data.Direction]uint32)}, nil
+}
+
+// WritePacket records one Minecraft frame (a varint packet ID followed by
+// its fields, with no length prefix) travelling in direction dir at time
+// ts.
+func (wr *Writer) WritePacket(dir data.Direction, frame []byte, ts time.Time) error {
+	seq := wr.seq[dir]
+	wr.seq[dir] = seq + uint32(len(frame))
+
+	eth := buildEthernetFrame(dir, seq, frame)
+	return writeEnhancedPacketBlock(wr.w, 0, ts, eth)
+}
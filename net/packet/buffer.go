@@ -0,0 +1,52 @@
+package packet
+
+import (
+	"fmt"
+	"io"
+)
+
+// ByteArray is an opaque byte slice prefixed by its length as a VarInt,
+// used for the "buffer" protocol type.
+type ByteArray []byte
+
+func (b ByteArray) Marshal(w io.Writer) error {
+	if err := (VarInt(len(b))).Marshal(w); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func (b *ByteArray) Unmarshal(r io.Reader) error {
+	var n VarInt
+	if err := n.Unmarshal(r); err != nil {
+		return err
+	}
+	if n < 0 || n > maxStringLen {
+		return fmt.Errorf("packet: buffer length %d out of range", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	*b = buf
+	return nil
+}
+
+// RestBuffer is every byte remaining in the packet, with no length prefix.
+// It must be the last field of a packet.
+type RestBuffer []byte
+
+func (b RestBuffer) Marshal(w io.Writer) error {
+	_, err := w.Write(b)
+	return err
+}
+
+func (b *RestBuffer) Unmarshal(r io.Reader) error {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	*b = buf
+	return nil
+}
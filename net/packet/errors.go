@@ -0,0 +1,8 @@
+package packet
+
+import "errors"
+
+// ErrUnsupportedField is returned by generated packets that contain a
+// protocol.json field shape the generator doesn't translate yet (most
+// commonly a "switch" field whose cases it can't resolve automatically).
+var ErrUnsupportedField = errors.New("packet: field type not supported by codegen, needs a hand-written Marshal/Unmarshal")
@@ -0,0 +1,177 @@
+package packet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// maxNBTElems bounds how many elements a byteArray/intArray/longArray/list
+// tag's count may claim, so a corrupted or adversarial tag can't drive an
+// unbounded allocation the way an unchecked count straight off the wire
+// would (the same kind of bound String/ByteArray apply via maxStringLen).
+const maxNBTElems = 1 << 20
+
+// NBT carries a raw, already-encoded named NBT tag, as used by the "nbt"
+// and "slot" protocol types. Unmarshal walks just enough of the tag tree
+// to find where it ends, without decoding it into a structured value;
+// callers that need the parsed contents should decode Raw with a
+// dedicated NBT library.
+type NBT struct {
+	Raw []byte
+}
+
+func (n NBT) Marshal(w io.Writer) error {
+	_, err := w.Write(n.Raw)
+	return err
+}
+
+func (n *NBT) Unmarshal(r io.Reader) error {
+	buf, err := readNBTTag(r)
+	if err != nil {
+		return err
+	}
+	n.Raw = buf
+	return nil
+}
+
+const (
+	tagEnd byte = iota
+	tagByte
+	tagShort
+	tagInt
+	tagLong
+	tagFloat
+	tagDouble
+	tagByteArray
+	tagString
+	tagList
+	tagCompound
+	tagIntArray
+	tagLongArray
+)
+
+// readNBTTag consumes one named tag (id, name, payload) and returns the
+// exact bytes it occupied on the wire.
+func readNBTTag(r io.Reader) ([]byte, error) {
+	var buf []byte
+	id, err := readN(r, 1)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, id...)
+	if id[0] == tagEnd {
+		return buf, nil
+	}
+
+	nameLen, err := readN(r, 2)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, nameLen...)
+	name, err := readN(r, int(binary.BigEndian.Uint16(nameLen)))
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, name...)
+
+	payload, err := readNBTPayload(r, id[0])
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, payload...), nil
+}
+
+// readNBTPayload consumes the value of a tag whose type is already known
+// (used both for named tags and for list elements, which omit the id and
+// name that readNBTTag reads).
+func readNBTPayload(r io.Reader, id byte) ([]byte, error) {
+	switch id {
+	case tagByte:
+		return readN(r, 1)
+	case tagShort:
+		return readN(r, 2)
+	case tagInt, tagFloat:
+		return readN(r, 4)
+	case tagLong, tagDouble:
+		return readN(r, 8)
+	case tagString:
+		lenBuf, err := readN(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		data, err := readN(r, int(binary.BigEndian.Uint16(lenBuf)))
+		if err != nil {
+			return nil, err
+		}
+		return append(lenBuf, data...), nil
+	case tagByteArray:
+		return readCountedArray(r, 1, 4)
+	case tagIntArray:
+		return readCountedArray(r, 4, 4)
+	case tagLongArray:
+		return readCountedArray(r, 8, 4)
+	case tagList:
+		elemID, err := readN(r, 1)
+		if err != nil {
+			return nil, err
+		}
+		countBuf, err := readN(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		buf := append(elemID, countBuf...)
+		count := binary.BigEndian.Uint32(countBuf)
+		if count > maxNBTElems {
+			return nil, fmt.Errorf("packet: NBT list length %d out of range", count)
+		}
+		for i := uint32(0); i < count; i++ {
+			payload, err := readNBTPayload(r, elemID[0])
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, payload...)
+		}
+		return buf, nil
+	case tagCompound:
+		var buf []byte
+		for {
+			tag, err := readNBTTag(r)
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, tag...)
+			if tag[0] == tagEnd {
+				return buf, nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("packet: unknown NBT tag id %d", id)
+	}
+}
+
+// readCountedArray reads a 4-byte big-endian element count followed by
+// count elements of elemSize bytes each.
+func readCountedArray(r io.Reader, elemSize, countSize int) ([]byte, error) {
+	countBuf, err := readN(r, countSize)
+	if err != nil {
+		return nil, err
+	}
+	count := int(binary.BigEndian.Uint32(countBuf))
+	if count < 0 || count > maxNBTElems {
+		return nil, fmt.Errorf("packet: NBT array length %d out of range", count)
+	}
+	data, err := readN(r, count*elemSize)
+	if err != nil {
+		return nil, err
+	}
+	return append(countBuf, data...), nil
+}
+
+func readN(r io.Reader, n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,32 @@
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestNBTRejectsOversizedArrayCount(t *testing.T) {
+	// tagByteArray payload: a 4-byte count with no element bytes behind it.
+	var countBuf [4]byte
+	binary.BigEndian.PutUint32(countBuf[:], 0xFFFFFFFF)
+
+	var tag NBT
+	err := tag.Unmarshal(bytes.NewReader(append([]byte{tagByteArray, 0, 0}, countBuf[:]...)))
+	if err == nil {
+		t.Fatal("Unmarshal with a huge byteArray count: got nil error, want an out-of-range error")
+	}
+}
+
+func TestNBTRejectsOversizedListCount(t *testing.T) {
+	// tagList payload: element type id, then a 4-byte count with no elements
+	// behind it.
+	var countBuf [4]byte
+	binary.BigEndian.PutUint32(countBuf[:], 0xFFFFFFFF)
+
+	var tag NBT
+	err := tag.Unmarshal(bytes.NewReader(append([]byte{tagList, 0, 0, tagByte}, countBuf[:]...)))
+	if err == nil {
+		t.Fatal("Unmarshal with a huge list count: got nil error, want an out-of-range error")
+	}
+}
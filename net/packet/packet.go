@@ -0,0 +1,14 @@
+// Package packet implements the primitive field types used to encode and
+// decode the fields of a Minecraft protocol packet. Generated packet
+// structs under data/ compose these into full packets.
+package packet
+
+import "io"
+
+// Field is anything that can read and write its own wire representation.
+// Every non-array field of a generated packet struct, every element of an
+// array field, and every generated packet struct itself, implements Field.
+type Field interface {
+	Marshal(w io.Writer) error
+	Unmarshal(r io.Reader) error
+}
@@ -0,0 +1,69 @@
+package packet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVarIntRoundTrip(t *testing.T) {
+	for _, want := range []VarInt{0, 1, -1, 127, 128, 255, 2097151, -2147483648, 2147483647} {
+		var buf bytes.Buffer
+		if err := want.Marshal(&buf); err != nil {
+			t.Fatalf("Marshal(%d): %v", want, err)
+		}
+		var got VarInt
+		if err := got.Unmarshal(&buf); err != nil {
+			t.Fatalf("Unmarshal(%d): %v", want, err)
+		}
+		if got != want {
+			t.Errorf("VarInt round-trip: got %d, want %d", got, want)
+		}
+	}
+}
+
+func TestVarIntTooBig(t *testing.T) {
+	buf := bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	var v VarInt
+	if err := v.Unmarshal(buf); err != ErrVarIntTooBig {
+		t.Fatalf("Unmarshal of an over-long varint: got %v, want ErrVarIntTooBig", err)
+	}
+}
+
+func TestVarLongRoundTrip(t *testing.T) {
+	for _, want := range []VarLong{0, 1, -1, 128, 1 << 40, -(1 << 40)} {
+		var buf bytes.Buffer
+		if err := want.Marshal(&buf); err != nil {
+			t.Fatalf("Marshal(%d): %v", want, err)
+		}
+		var got VarLong
+		if err := got.Unmarshal(&buf); err != nil {
+			t.Fatalf("Unmarshal(%d): %v", want, err)
+		}
+		if got != want {
+			t.Errorf("VarLong round-trip: got %d, want %d", got, want)
+		}
+	}
+}
+
+func TestPositionRoundTrip(t *testing.T) {
+	cases := []Position{
+		{X: 0, Y: 0, Z: 0},
+		{X: 1, Y: 1, Z: 1},
+		{X: -1, Y: -1, Z: -1},
+		{X: 18615, Y: 64, Z: -32000},
+		{X: 33554431, Y: 2047, Z: -33554432}, // extremes of the 26/12/26-bit fields
+	}
+	for _, want := range cases {
+		var buf bytes.Buffer
+		if err := want.Marshal(&buf); err != nil {
+			t.Fatalf("Marshal(%+v): %v", want, err)
+		}
+		var got Position
+		if err := got.Unmarshal(&buf); err != nil {
+			t.Fatalf("Unmarshal(%+v): %v", want, err)
+		}
+		if got != want {
+			t.Errorf("Position round-trip: got %+v, want %+v", got, want)
+		}
+	}
+}
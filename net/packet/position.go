@@ -0,0 +1,28 @@
+package packet
+
+import "io"
+
+// Position is a block position, packed into a single 64-bit integer on the
+// wire as 26 bits of X, 26 bits of Z and 12 bits of Y (the layout used
+// since the 1.14 protocol).
+type Position struct {
+	X, Y, Z int
+}
+
+func (p Position) Marshal(w io.Writer) error {
+	v := (int64(p.X)&0x3FFFFFF)<<38 | (int64(p.Z)&0x3FFFFFF)<<12 | (int64(p.Y) & 0xFFF)
+	return Long(v).Marshal(w)
+}
+
+func (p *Position) Unmarshal(r io.Reader) error {
+	var v Long
+	if err := v.Unmarshal(r); err != nil {
+		return err
+	}
+	// Each shift pair clears the bits outside the field and arithmetically
+	// shifts back, which sign-extends the field in the same step.
+	p.X = int(v >> 38)
+	p.Y = int(v << 52 >> 52)
+	p.Z = int(v << 26 >> 38)
+	return nil
+}
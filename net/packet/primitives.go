@@ -0,0 +1,126 @@
+package packet
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// Boolean is a single byte, 0x00 for false and 0x01 for true.
+type Boolean bool
+
+func (b Boolean) Marshal(w io.Writer) error {
+	v := byte(0)
+	if b {
+		v = 1
+	}
+	_, err := w.Write([]byte{v})
+	return err
+}
+
+func (b *Boolean) Unmarshal(r io.Reader) error {
+	var buf [1]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return err
+	}
+	*b = buf[0] != 0
+	return nil
+}
+
+// Byte is a signed 8-bit integer.
+type Byte int8
+
+func (v Byte) Marshal(w io.Writer) error {
+	_, err := w.Write([]byte{byte(v)})
+	return err
+}
+
+func (v *Byte) Unmarshal(r io.Reader) error {
+	var buf [1]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return err
+	}
+	*v = Byte(buf[0])
+	return nil
+}
+
+// UByte is an unsigned 8-bit integer.
+type UByte uint8
+
+func (v UByte) Marshal(w io.Writer) error {
+	_, err := w.Write([]byte{byte(v)})
+	return err
+}
+
+func (v *UByte) Unmarshal(r io.Reader) error {
+	var buf [1]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return err
+	}
+	*v = UByte(buf[0])
+	return nil
+}
+
+// Short is a signed, big-endian 16-bit integer.
+type Short int16
+
+func (v Short) Marshal(w io.Writer) error { return binary.Write(w, binary.BigEndian, int16(v)) }
+func (v *Short) Unmarshal(r io.Reader) error {
+	return binary.Read(r, binary.BigEndian, (*int16)(v))
+}
+
+// UShort is an unsigned, big-endian 16-bit integer.
+type UShort uint16
+
+func (v UShort) Marshal(w io.Writer) error { return binary.Write(w, binary.BigEndian, uint16(v)) }
+func (v *UShort) Unmarshal(r io.Reader) error {
+	return binary.Read(r, binary.BigEndian, (*uint16)(v))
+}
+
+// Int is a signed, big-endian 32-bit integer.
+type Int int32
+
+func (v Int) Marshal(w io.Writer) error { return binary.Write(w, binary.BigEndian, int32(v)) }
+func (v *Int) Unmarshal(r io.Reader) error {
+	return binary.Read(r, binary.BigEndian, (*int32)(v))
+}
+
+// Long is a signed, big-endian 64-bit integer.
+type Long int64
+
+func (v Long) Marshal(w io.Writer) error { return binary.Write(w, binary.BigEndian, int64(v)) }
+func (v *Long) Unmarshal(r io.Reader) error {
+	return binary.Read(r, binary.BigEndian, (*int64)(v))
+}
+
+// Float is a big-endian IEEE 754 32-bit float.
+type Float float32
+
+func (v Float) Marshal(w io.Writer) error {
+	return binary.Write(w, binary.BigEndian, math.Float32bits(float32(v)))
+}
+
+func (v *Float) Unmarshal(r io.Reader) error {
+	var bits uint32
+	if err := binary.Read(r, binary.BigEndian, &bits); err != nil {
+		return err
+	}
+	*v = Float(math.Float32frombits(bits))
+	return nil
+}
+
+// Double is a big-endian IEEE 754 64-bit float.
+type Double float64
+
+func (v Double) Marshal(w io.Writer) error {
+	return binary.Write(w, binary.BigEndian, math.Float64bits(float64(v)))
+}
+
+func (v *Double) Unmarshal(r io.Reader) error {
+	var bits uint64
+	if err := binary.Read(r, binary.BigEndian, &bits); err != nil {
+		return err
+	}
+	*v = Double(math.Float64frombits(bits))
+	return nil
+}
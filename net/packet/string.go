@@ -0,0 +1,50 @@
+package packet
+
+import (
+	"fmt"
+	"io"
+)
+
+// maxStringLen bounds how large a String/ByteArray this package will
+// allocate for, so a desynchronized stream can't be used to exhaust memory.
+const maxStringLen = 1 << 20
+
+// String is a UTF-8 string, prefixed by its length in bytes as a VarInt.
+type String string
+
+func (s String) Marshal(w io.Writer) error {
+	if err := (VarInt(len(s))).Marshal(w); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, string(s))
+	return err
+}
+
+func (s *String) Unmarshal(r io.Reader) error {
+	var n VarInt
+	if err := n.Unmarshal(r); err != nil {
+		return err
+	}
+	if n < 0 || n > maxStringLen {
+		return fmt.Errorf("packet: string length %d out of range", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	*s = String(buf)
+	return nil
+}
+
+// Identifier is a namespaced string such as "minecraft:stone".
+type Identifier string
+
+func (id Identifier) Marshal(w io.Writer) error       { return String(id).Marshal(w) }
+func (id *Identifier) Unmarshal(r io.Reader) error {
+	var s String
+	if err := s.Unmarshal(r); err != nil {
+		return err
+	}
+	*id = Identifier(s)
+	return nil
+}
@@ -0,0 +1,16 @@
+package packet
+
+import "io"
+
+// UUID is a 128-bit UUID, written as its 16 raw bytes in big-endian order.
+type UUID [16]byte
+
+func (u UUID) Marshal(w io.Writer) error {
+	_, err := w.Write(u[:])
+	return err
+}
+
+func (u *UUID) Unmarshal(r io.Reader) error {
+	_, err := io.ReadFull(r, u[:])
+	return err
+}
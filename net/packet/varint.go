@@ -0,0 +1,94 @@
+package packet
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrVarIntTooBig is returned when a varint/varlong on the wire uses more
+// bytes than its type could ever need, which usually means the stream is
+// desynchronized.
+var ErrVarIntTooBig = errors.New("packet: varint is too big")
+
+// VarInt is a variable-length encoded int32, as used for packet lengths,
+// packet IDs and most integer fields in the protocol.
+type VarInt int32
+
+func (v VarInt) Marshal(w io.Writer) error {
+	u := uint32(v)
+	var buf [5]byte
+	n := 0
+	for {
+		b := byte(u & 0x7F)
+		u >>= 7
+		if u != 0 {
+			b |= 0x80
+		}
+		buf[n] = b
+		n++
+		if u == 0 {
+			break
+		}
+	}
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+func (v *VarInt) Unmarshal(r io.Reader) error {
+	var result uint32
+	var shift uint
+	var buf [1]byte
+	for i := 0; i < 5; i++ {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return err
+		}
+		result |= uint32(buf[0]&0x7F) << shift
+		if buf[0]&0x80 == 0 {
+			*v = VarInt(int32(result))
+			return nil
+		}
+		shift += 7
+	}
+	return ErrVarIntTooBig
+}
+
+// VarLong is a variable-length encoded int64.
+type VarLong int64
+
+func (v VarLong) Marshal(w io.Writer) error {
+	u := uint64(v)
+	var buf [10]byte
+	n := 0
+	for {
+		b := byte(u & 0x7F)
+		u >>= 7
+		if u != 0 {
+			b |= 0x80
+		}
+		buf[n] = b
+		n++
+		if u == 0 {
+			break
+		}
+	}
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+func (v *VarLong) Unmarshal(r io.Reader) error {
+	var result uint64
+	var shift uint
+	var buf [1]byte
+	for i := 0; i < 10; i++ {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return err
+		}
+		result |= uint64(buf[0]&0x7F) << shift
+		if buf[0]&0x80 == 0 {
+			*v = VarLong(int64(result))
+			return nil
+		}
+		shift += 7
+	}
+	return ErrVarIntTooBig
+}